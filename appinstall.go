@@ -0,0 +1,144 @@
+package prcomment
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/bobg/errors"
+	"github.com/google/go-github/v62/github"
+	"golang.org/x/oauth2"
+)
+
+// Option configures a Commenter constructed with NewCommenterFromAppInstallation.
+type Option func(*appInstallationConfig)
+
+type appInstallationConfig struct {
+	client *github.Client // used to mint JWTs and request installation tokens
+}
+
+// WithAppClient tells NewCommenterFromAppInstallation to use client,
+// rather than a default client pointed at github.com,
+// when minting JWTs and requesting installation tokens.
+// This is how to reach a GitHub Enterprise server,
+// via a client built with [github.Client.WithEnterpriseURLs].
+func WithAppClient(client *github.Client) Option {
+	return func(c *appInstallationConfig) {
+		c.client = client
+	}
+}
+
+// NewCommenterFromAppInstallation creates a new Commenter authenticated as a GitHub App installation,
+// rather than with a user's OAuth token.
+//
+// It signs short-lived JWTs with the App's private key (appID and PEM-encoded privateKeyPEM)
+// and exchanges them for tokens scoped to the given installationID,
+// caching each installation token until shortly before it expires and minting a new one as needed.
+// This is the standard way for a bot to comment on pull requests across an organization:
+// it gets the higher rate limits and per-repo scoping of a GitHub App,
+// without requiring a personal access token.
+func NewCommenterFromAppInstallation(ctx context.Context, appID, installationID int64, privateKeyPEM []byte, body func(context.Context, *PullRequest) (string, error), opts ...Option) (*Commenter, error) {
+	key, err := parseAppPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing app private key")
+	}
+
+	cfg := &appInstallationConfig{client: github.NewClient(nil)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	src := oauth2.ReuseTokenSource(nil, &installationTokenSource{
+		ctx:            ctx,
+		client:         cfg.client,
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+	})
+
+	client := github.NewClient(oauth2.NewClient(ctx, src))
+	client.BaseURL, client.UploadURL = cfg.client.BaseURL, cfg.client.UploadURL
+
+	return NewCommenter(client, body), nil
+}
+
+// installationTokenSource is an oauth2.TokenSource that mints a GitHub App installation access token
+// by signing a fresh JWT and exchanging it via the "create an installation access token" API.
+type installationTokenSource struct {
+	ctx            context.Context
+	client         *github.Client
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	appJWT, err := signAppJWT(s.appID, s.key, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "signing app JWT")
+	}
+
+	tok, _, err := s.client.WithAuthToken(appJWT).Apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating installation token")
+	}
+
+	return &oauth2.Token{
+		AccessToken: tok.GetToken(),
+		Expiry:      tok.GetExpiresAt().Time,
+	}, nil
+}
+
+// signAppJWT produces a JWT of the kind GitHub requires for App-level API calls
+// (e.g. minting an installation token): RS256-signed, with an "iss" claim of appID
+// and a short "iat"/"exp" window around now, per
+// https://docs.github.com/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app.
+func signAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil,
+		`{"iat":%d,"exp":%d,"iss":"%d"}`,
+		now.Add(-30*time.Second).Unix(),
+		now.Add(9*time.Minute).Unix(),
+		appID,
+	))
+
+	signingInput := header + "." + claims
+	hashed := sha256.Sum256([]byte(signingInput))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", errors.Wrap(err, "signing JWT")
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parseAppPrivateKey parses a GitHub App's PEM-encoded private key,
+// as downloaded from the App's settings page, in either PKCS#1 or PKCS#8 form.
+func parseAppPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded key found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing PKCS8 private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,118 @@
+package prcomment
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAppJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+
+	now := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+	tok, err := signAppJWT(12345, key, now)
+	if err != nil {
+		t.Fatalf("signAppJWT: %v", err)
+	}
+
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		t.Fatalf("got %d dot-separated parts, want 3", len(parts))
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	if string(header) != `{"alg":"RS256","typ":"JWT"}` {
+		t.Errorf("header = %s, want RS256/JWT header", header)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	var claims struct {
+		IAT int64  `json:"iat"`
+		EXP int64  `json:"exp"`
+		ISS string `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("unmarshaling claims: %v", err)
+	}
+	if claims.ISS != "12345" {
+		t.Errorf("iss = %q, want %q", claims.ISS, "12345")
+	}
+	if want := now.Add(-30 * time.Second).Unix(); claims.IAT != want {
+		t.Errorf("iat = %d, want %d", claims.IAT, want)
+	}
+	if want := now.Add(9 * time.Minute).Unix(); claims.EXP != want {
+		t.Errorf("exp = %d, want %d", claims.EXP, want)
+	}
+}
+
+func TestParseAppPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+
+	t.Run("PKCS1", func(t *testing.T) {
+		der := x509.MarshalPKCS1PrivateKey(rsaKey)
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})
+		key, err := parseAppPrivateKey(pemBytes)
+		if err != nil {
+			t.Fatalf("parseAppPrivateKey: %v", err)
+		}
+		if !key.Equal(rsaKey) {
+			t.Error("parsed key does not match the original")
+		}
+	})
+
+	t.Run("PKCS8", func(t *testing.T) {
+		der, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+		if err != nil {
+			t.Fatalf("marshaling PKCS8 key: %v", err)
+		}
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		key, err := parseAppPrivateKey(pemBytes)
+		if err != nil {
+			t.Fatalf("parseAppPrivateKey: %v", err)
+		}
+		if !key.Equal(rsaKey) {
+			t.Error("parsed key does not match the original")
+		}
+	})
+
+	t.Run("non-RSA PKCS8", func(t *testing.T) {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("generating EC key: %v", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+		if err != nil {
+			t.Fatalf("marshaling PKCS8 key: %v", err)
+		}
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		if _, err := parseAppPrivateKey(pemBytes); err == nil {
+			t.Error("expected an error for a non-RSA key")
+		}
+	})
+
+	t.Run("not PEM", func(t *testing.T) {
+		if _, err := parseAppPrivateKey([]byte("not a pem block")); err == nil {
+			t.Error("expected an error for non-PEM input")
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package prcomment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// markerPattern matches the hidden marker that WithMarker embeds at the top of a comment body:
+// "<!-- prcomment:ID=<id> hash=<sha256 of the body that follows it> -->".
+var markerPattern = regexp.MustCompile(`^<!-- prcomment:ID=(\S+) hash=([0-9a-f]{64}) -->`)
+
+// ExtractMarker extracts the ID and hash from a WithMarker-embedded marker at the start of body,
+// if there is one.
+func ExtractMarker(body string) (id, hash string, ok bool) {
+	m := markerPattern.FindStringSubmatch(body)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// WithMarker returns a copy of c that embeds a hidden marker comment at the top of the generated body,
+// "<!-- prcomment:ID=id hash=SHA256 -->", keyed by the caller-supplied id.
+// When IsComment is nil, AddOrUpdate uses the marker to find this bot's own earlier comment,
+// so that multiple independent bots can coexist on one PR without stepping on each other's comments.
+// It also compares the stored hash against the new body's hash and skips the update entirely when they match,
+// avoiding a needless API call and PR notification.
+func (c Commenter) WithMarker(id string) Commenter {
+	c.markerID = id
+	return c
+}
+
+func (c Commenter) isMarkerComment(comment *Comment) bool {
+	id, _, ok := ExtractMarker(comment.Body)
+	return ok && id == c.markerID
+}
+
+func withMarkerHeader(id, body string) (marked, hash string) {
+	hash = bodyHash(body)
+	marked = fmt.Sprintf("<!-- prcomment:ID=%s hash=%s -->\n\n%s", id, hash, body)
+	return marked, hash
+}
+
+func bodyHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
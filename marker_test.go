@@ -0,0 +1,108 @@
+package prcomment
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeProvider struct {
+	comments []*Comment
+	edited   bool
+	created  bool
+	lastBody string
+}
+
+func (p *fakeProvider) GetPR(ctx context.Context, owner, reponame string, number int) (*PullRequest, error) {
+	return &PullRequest{Number: number}, nil
+}
+
+func (p *fakeProvider) ListComments(ctx context.Context, owner, reponame string, number int) ([]*Comment, error) {
+	return p.comments, nil
+}
+
+func (p *fakeProvider) CreateComment(ctx context.Context, owner, reponame string, number int, body string) (*Comment, error) {
+	p.created = true
+	p.lastBody = body
+	return &Comment{ID: 1, Body: body}, nil
+}
+
+func (p *fakeProvider) EditComment(ctx context.Context, owner, reponame string, number int, commentID int64, body string) error {
+	p.edited = true
+	p.lastBody = body
+	return nil
+}
+
+func TestWithMarkerHeaderAndExtractMarker(t *testing.T) {
+	marked, hash := withMarkerHeader("my-bot", "hello world")
+
+	gotID, gotHash, ok := ExtractMarker(marked)
+	if !ok {
+		t.Fatalf("ExtractMarker(%q) found no marker", marked)
+	}
+	if gotID != "my-bot" {
+		t.Errorf("id = %q, want %q", gotID, "my-bot")
+	}
+	if gotHash != hash {
+		t.Errorf("hash = %q, want %q", gotHash, hash)
+	}
+	if got := bodyHash("hello world"); got != hash {
+		t.Errorf("withMarkerHeader hash %q does not match bodyHash %q", hash, got)
+	}
+}
+
+func TestWithMarkerHeaderChangesHashWithBody(t *testing.T) {
+	_, hash1 := withMarkerHeader("my-bot", "body one")
+	_, hash2 := withMarkerHeader("my-bot", "body two")
+	if hash1 == hash2 {
+		t.Error("expected different bodies to produce different hashes")
+	}
+}
+
+func TestExtractMarkerNoMarker(t *testing.T) {
+	if _, _, ok := ExtractMarker("just a plain comment body"); ok {
+		t.Error("expected ok=false for a body with no marker")
+	}
+}
+
+func TestIsMarkerComment(t *testing.T) {
+	body, _ := withMarkerHeader("bot-a", "some content")
+	c := Commenter{}.WithMarker("bot-a")
+
+	if !c.isMarkerComment(&Comment{Body: body}) {
+		t.Error("expected comment with matching marker ID to match")
+	}
+
+	other := Commenter{}.WithMarker("bot-b")
+	if other.isMarkerComment(&Comment{Body: body}) {
+		t.Error("expected comment with a different marker ID not to match")
+	}
+}
+
+func TestPostOrUpdateSkipsUnchangedMarkedBody(t *testing.T) {
+	existing, _ := withMarkerHeader("bot-a", "same content")
+	p := &fakeProvider{comments: []*Comment{{ID: 1, Body: existing}}}
+	c := Commenter{provider: p}.WithMarker("bot-a")
+
+	if err := c.postOrUpdate(context.Background(), "owner", "repo", 1, "same content"); err != nil {
+		t.Fatalf("postOrUpdate: %v", err)
+	}
+	if p.edited || p.created {
+		t.Error("expected no API call when the marked body is unchanged")
+	}
+}
+
+func TestPostOrUpdateEditsOnChangedMarkedBody(t *testing.T) {
+	existing, _ := withMarkerHeader("bot-a", "old content")
+	p := &fakeProvider{comments: []*Comment{{ID: 1, Body: existing}}}
+	c := Commenter{provider: p}.WithMarker("bot-a")
+
+	if err := c.postOrUpdate(context.Background(), "owner", "repo", 1, "new content"); err != nil {
+		t.Fatalf("postOrUpdate: %v", err)
+	}
+	if !p.edited {
+		t.Error("expected EditComment to be called when the marked body changed")
+	}
+	if p.created {
+		t.Error("did not expect CreateComment to be called when an existing marked comment was found")
+	}
+}
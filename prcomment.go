@@ -1,3 +1,5 @@
+// Package prcomment adds a comment to a pull (or merge) request,
+// or updates one it has added before, on GitHub, GitLab, or Gitea.
 package prcomment
 
 import (
@@ -11,39 +13,36 @@ import (
 	"github.com/google/go-github/v62/github"
 )
 
-// Commenter is an object whose method AddOrUpdate adds a comment to a GitHub pull request
+// Commenter is an object whose method AddOrUpdate adds a comment to a pull request
 // or optionally updates an existing one.
 type Commenter struct {
 	// IsComment, if non-nil, is a function that returns true if a given comment is the one to update.
-	IsComment func(*github.IssueComment) bool
+	// WithMarker installs a default based on its own hidden marker if this is left nil.
+	IsComment func(*Comment) bool
 
-	body   func(context.Context, *github.PullRequest) (string, error)
-	prs    prsIntf
-	issues issuesIntf
+	body     func(context.Context, *PullRequest) (string, error)
+	provider Provider
+	markerID string
 }
 
-// NewCommenter creates a new Commenter object.
+// NewCommenter creates a new Commenter object backed by the GitHub REST API.
 // The body function is called to generate the new or updated comment body from a given pull request.
-func NewCommenter(client *github.Client, body func(context.Context, *github.PullRequest) (string, error)) *Commenter {
-	return &Commenter{
-		body:   body,
-		prs:    client.PullRequests,
-		issues: client.Issues,
-	}
+func NewCommenter(client *github.Client, body func(context.Context, *PullRequest) (string, error)) *Commenter {
+	return NewCommenterWithProvider(NewGitHubProvider(client), body)
 }
 
-type prsIntf interface {
-	Get(ctx context.Context, owner, reponame string, number int) (*github.PullRequest, *github.Response, error)
-}
-
-type issuesIntf interface {
-	CreateComment(ctx context.Context, owner, reponame string, num int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
-	EditComment(ctx context.Context, owner, reponame string, commentID int64, newComment *github.IssueComment) (*github.IssueComment, *github.Response, error)
-	ListComments(ctx context.Context, owner, reponame string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+// NewCommenterWithProvider creates a new Commenter object backed by an arbitrary Provider,
+// e.g. a GitLabProvider or GiteaProvider for teams on a self-hosted forge.
+// The body function is called to generate the new or updated comment body from a given pull request.
+func NewCommenterWithProvider(provider Provider, body func(context.Context, *PullRequest) (string, error)) *Commenter {
+	return &Commenter{
+		body:     body,
+		provider: provider,
+	}
 }
 
 func (c Commenter) AddOrUpdate(ctx context.Context, owner, reponame string, prnum int) error {
-	pr, _, err := c.prs.Get(ctx, owner, reponame, prnum)
+	pr, err := c.provider.GetPR(ctx, owner, reponame, prnum)
 	if err != nil {
 		return errors.Wrap(err, "getting pull request")
 	}
@@ -52,47 +51,116 @@ func (c Commenter) AddOrUpdate(ctx context.Context, owner, reponame string, prnu
 	if err != nil {
 		return errors.Wrap(err, "getting comment body")
 	}
-	issueComment := &github.IssueComment{Body: &body}
 
-	comments, _, err := c.issues.ListComments(ctx, owner, reponame, prnum, nil)
+	return c.postOrUpdate(ctx, owner, reponame, prnum, body)
+}
+
+// postOrUpdate adds body as a new PR comment, or edits an existing one found via IsComment or WithMarker,
+// given an already-rendered body. AddOrUpdate renders the body itself; TemplateCommenter renders its own.
+func (c Commenter) postOrUpdate(ctx context.Context, owner, reponame string, prnum int, body string) error {
+	var hash string
+	isComment := c.IsComment
+	if c.markerID != "" {
+		body, hash = withMarkerHeader(c.markerID, body)
+		if isComment == nil {
+			isComment = c.isMarkerComment
+		}
+	}
+
+	comments, err := c.provider.ListComments(ctx, owner, reponame, prnum)
 	if err != nil {
 		return errors.Wrap(err, "listing PR comments")
 	}
 
-	if c.IsComment != nil {
+	if isComment != nil {
 		for _, comment := range comments {
-			if c.IsComment(comment) {
-				_, _, err = c.issues.EditComment(ctx, owner, reponame, *comment.ID, issueComment)
-				return errors.Wrap(err, "updating PR comment")
+			if !isComment(comment) {
+				continue
 			}
+			if c.markerID != "" {
+				if _, oldHash, ok := ExtractMarker(comment.Body); ok && oldHash == hash {
+					return nil
+				}
+			}
+			return errors.Wrap(c.provider.EditComment(ctx, owner, reponame, prnum, comment.ID, body), "updating PR comment")
 		}
 	}
 
-	_, _, err = c.issues.CreateComment(ctx, owner, reponame, prnum, issueComment)
+	_, err = c.provider.CreateComment(ctx, owner, reponame, prnum, body)
 	return errors.Wrap(err, "adding PR comment")
 }
 
-// ParsePR parses a GitHub pull-request URL,
-// which should have the form http(s)://HOST/OWNER/REPO/pull/NUMBER.
-func ParsePR(pr string) (host, owner, reponame string, prnum int, err error) {
-	u, err := url.Parse(pr)
-	if err != nil {
-		err = errors.Wrap(err, "parsing GitHub pull-request URL")
+// ParsePR parses a pull- (or merge-) request URL, which should have one of the forms
+//
+//	http(s)://HOST/OWNER/REPO/pull/NUMBER           (GitHub)
+//	http(s)://HOST/OWNER/REPO/pulls/NUMBER          (Gitea)
+//	http(s)://HOST/OWNER/REPO/-/merge_requests/NUMBER  (GitLab)
+//
+// The returned ProviderKind is looked up in the registry populated by RegisterProviderHost,
+// falling back to a guess based on the shape of the URL's path.
+func ParsePR(pr string) (kind ProviderKind, host, owner, reponame string, prnum int, err error) {
+	u, uerr := url.Parse(pr)
+	if uerr != nil {
+		err = errors.Wrap(uerr, "parsing pull-request URL")
 		return
 	}
+	host = u.Host
 	path := strings.TrimLeft(u.Path, "/")
 	parts := strings.Split(path, "/")
+
+	var ok bool
+	kind, ok = providerHosts[host]
+	if !ok {
+		kind = inferProviderKind(parts)
+	}
+
+	switch kind {
+	case GitLab:
+		owner, reponame, prnum, err = parseGitLabPRPath(parts)
+	case Gitea:
+		owner, reponame, prnum, err = parseSegmentedPRPath(parts, "pulls")
+	default:
+		owner, reponame, prnum, err = parseSegmentedPRPath(parts, "pull")
+	}
+	return
+}
+
+func inferProviderKind(parts []string) ProviderKind {
+	if len(parts) >= 4 && parts[2] == "-" {
+		return GitLab
+	}
+	if len(parts) >= 4 && parts[2] == "pulls" {
+		return Gitea
+	}
+	return GitHub
+}
+
+func parseSegmentedPRPath(parts []string, segment string) (owner, reponame string, prnum int, err error) {
 	if len(parts) < 4 {
 		err = fmt.Errorf("too few path elements in pull-request URL (got %d, want 4)", len(parts))
 		return
 	}
-	if parts[2] != "pull" {
+	if parts[2] != segment {
 		err = fmt.Errorf("pull-request URL not in expected format")
 		return
 	}
-	host = u.Host
 	owner, reponame = parts[0], parts[1]
 	prnum, err = strconv.Atoi(parts[3])
-	err = errors.Wrap(err, "parsing number from GitHub pull-request URL")
+	err = errors.Wrap(err, "parsing number from pull-request URL")
+	return
+}
+
+func parseGitLabPRPath(parts []string) (owner, reponame string, prnum int, err error) {
+	if len(parts) < 5 {
+		err = fmt.Errorf("too few path elements in merge-request URL (got %d, want 5)", len(parts))
+		return
+	}
+	if parts[2] != "-" || parts[3] != "merge_requests" {
+		err = fmt.Errorf("merge-request URL not in expected format")
+		return
+	}
+	owner, reponame = parts[0], parts[1]
+	prnum, err = strconv.Atoi(parts[4])
+	err = errors.Wrap(err, "parsing number from merge-request URL")
 	return
 }
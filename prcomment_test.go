@@ -0,0 +1,82 @@
+package prcomment
+
+import "testing"
+
+func TestParsePR(t *testing.T) {
+	cases := []struct {
+		name      string
+		url       string
+		wantKind  ProviderKind
+		wantOwner string
+		wantRepo  string
+		wantNum   int
+		wantErr   bool
+	}{
+		{
+			name:      "github",
+			url:       "https://github.com/bobg/prcomment/pull/6",
+			wantKind:  GitHub,
+			wantOwner: "bobg",
+			wantRepo:  "prcomment",
+			wantNum:   6,
+		},
+		{
+			name:      "gitea inferred from path shape",
+			url:       "https://gitea.example.com/bobg/prcomment/pulls/6",
+			wantKind:  Gitea,
+			wantOwner: "bobg",
+			wantRepo:  "prcomment",
+			wantNum:   6,
+		},
+		{
+			name:      "gitlab inferred from path shape",
+			url:       "https://gitlab.example.com/bobg/prcomment/-/merge_requests/6",
+			wantKind:  GitLab,
+			wantOwner: "bobg",
+			wantRepo:  "prcomment",
+			wantNum:   6,
+		},
+		{
+			name:    "too few path elements",
+			url:     "https://github.com/bobg/pull/6",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kind, _, owner, reponame, prnum, err := ParsePR(c.url)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePR(%q): %v", c.url, err)
+			}
+			if kind != c.wantKind {
+				t.Errorf("kind = %q, want %q", kind, c.wantKind)
+			}
+			if owner != c.wantOwner || reponame != c.wantRepo || prnum != c.wantNum {
+				t.Errorf("got (%q, %q, %d), want (%q, %q, %d)", owner, reponame, prnum, c.wantOwner, c.wantRepo, c.wantNum)
+			}
+		})
+	}
+}
+
+func TestParsePRRegisteredHost(t *testing.T) {
+	RegisterProviderHost("git.example.com", GitLab)
+	defer delete(providerHosts, "git.example.com")
+
+	// A registered host's kind is taken from the registry (this GitLab-shaped path would also be inferred as GitLab).
+	kind, _, owner, reponame, prnum, err := ParsePR("https://git.example.com/bobg/prcomment/-/merge_requests/9")
+	if err != nil {
+		t.Fatalf("ParsePR: %v", err)
+	}
+	if kind != GitLab {
+		t.Errorf("kind = %q, want %q", kind, GitLab)
+	}
+	if owner != "bobg" || reponame != "prcomment" || prnum != 9 {
+		t.Errorf("got (%q, %q, %d), want (\"bobg\", \"prcomment\", 9)", owner, reponame, prnum)
+	}
+}
@@ -0,0 +1,57 @@
+package prcomment
+
+import "context"
+
+// PullRequest is provider-agnostic information about a pull (or merge) request.
+type PullRequest struct {
+	Number  int
+	Title   string
+	Body    string
+	State   string
+	User    string
+	HeadRef string
+	HeadSHA string
+	BaseRef string
+	BaseSHA string
+}
+
+// Comment is provider-agnostic information about a single comment on a pull/merge request.
+type Comment struct {
+	ID   int64
+	Body string
+	User string
+}
+
+// Provider is the set of forge operations a Commenter needs:
+// fetching a pull/merge request, and listing, creating, and editing comments on it.
+// GitHubProvider, GitLabProvider, and GiteaProvider are the built-in implementations.
+// A Provider implementation is responsible for its own pagination and rate-limit handling;
+// ListComments should return the complete list of comments, not just the first page.
+type Provider interface {
+	GetPR(ctx context.Context, owner, reponame string, number int) (*PullRequest, error)
+	ListComments(ctx context.Context, owner, reponame string, number int) ([]*Comment, error)
+	CreateComment(ctx context.Context, owner, reponame string, number int, body string) (*Comment, error)
+	EditComment(ctx context.Context, owner, reponame string, number int, commentID int64, body string) error
+}
+
+// ProviderKind identifies which forge a parsed pull/merge-request URL belongs to.
+type ProviderKind string
+
+const (
+	GitHub ProviderKind = "github"
+	GitLab ProviderKind = "gitlab"
+	Gitea  ProviderKind = "gitea"
+)
+
+// providerHosts maps hostnames to the ProviderKind that ParsePR should treat them as.
+// github.com is registered by default; register self-hosted GitLab or Gitea hosts with RegisterProviderHost.
+var providerHosts = map[string]ProviderKind{
+	"github.com": GitHub,
+}
+
+// RegisterProviderHost tells ParsePR to treat URLs on host as belonging to the given ProviderKind.
+// Use this for self-hosted GitLab or Gitea instances, whose hostnames ParsePR can't otherwise infer
+// from the shape of the URL alone.
+func RegisterProviderHost(host string, kind ProviderKind) {
+	providerHosts[host] = kind
+}
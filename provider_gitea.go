@@ -0,0 +1,163 @@
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bobg/errors"
+)
+
+// GiteaProvider implements Provider on top of the Gitea REST API.
+// Gitea treats a pull request's comments as issue comments on the same number,
+// so ListComments, CreateComment, and EditComment all go through the issues endpoints.
+//
+// https://docs.gitea.com/api/1.20/#tag/issue
+type GiteaProvider struct {
+	// BaseURL is the root of the Gitea instance, e.g. "https://gitea.example.com".
+	BaseURL string
+
+	// Token is a personal access token, sent as an "Authorization: token TOKEN" header.
+	Token string
+
+	// Client is the http.Client used to make requests. It defaults to http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewGiteaProvider creates a Provider backed by the Gitea instance at baseURL
+// (e.g. "https://gitea.example.com"), authenticating with token.
+func NewGiteaProvider(baseURL, token string) *GiteaProvider {
+	return &GiteaProvider{BaseURL: baseURL, Token: token}
+}
+
+type giteaPullRequest struct {
+	Number int         `json:"number"`
+	Title  string      `json:"title"`
+	Body   string      `json:"body"`
+	State  string      `json:"state"`
+	User   giteaUser   `json:"user"`
+	Head   giteaBranch `json:"head"`
+	Base   giteaBranch `json:"base"`
+}
+
+type giteaBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+type giteaComment struct {
+	ID   int64     `json:"id"`
+	Body string    `json:"body"`
+	User giteaUser `json:"user"`
+}
+
+func (p *GiteaProvider) GetPR(ctx context.Context, owner, reponame string, number int) (*PullRequest, error) {
+	var pr giteaPullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, reponame, number)
+	if err := p.do(ctx, http.MethodGet, path, nil, &pr); err != nil {
+		return nil, errors.Wrap(err, "getting pull request")
+	}
+	return &PullRequest{
+		Number:  pr.Number,
+		Title:   pr.Title,
+		Body:    pr.Body,
+		State:   pr.State,
+		User:    pr.User.Login,
+		HeadRef: pr.Head.Ref,
+		HeadSHA: pr.Head.SHA,
+		BaseRef: pr.Base.Ref,
+		BaseSHA: pr.Base.SHA,
+	}, nil
+}
+
+const giteaPageLimit = 50
+
+// ListComments returns all of the pull request's comments, following Gitea's page/limit pagination
+// (https://docs.gitea.com/api/1.20/#tag/issue/operation/issueGetComments) until a short page is seen.
+func (p *GiteaProvider) ListComments(ctx context.Context, owner, reponame string, number int) ([]*Comment, error) {
+	var out []*Comment
+	for page := 1; ; page++ {
+		var comments []giteaComment
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments?page=%d&limit=%d", owner, reponame, number, page, giteaPageLimit)
+		if err := p.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, errors.Wrap(err, "listing PR comments")
+		}
+		for _, c := range comments {
+			out = append(out, &Comment{ID: c.ID, Body: c.Body, User: c.User.Login})
+		}
+		if len(comments) < giteaPageLimit {
+			return out, nil
+		}
+	}
+}
+
+func (p *GiteaProvider) CreateComment(ctx context.Context, owner, reponame string, number int, body string) (*Comment, error) {
+	var c giteaComment
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, reponame, number)
+	if err := p.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &c); err != nil {
+		return nil, errors.Wrap(err, "adding PR comment")
+	}
+	return &Comment{ID: c.ID, Body: c.Body, User: c.User.Login}, nil
+}
+
+func (p *GiteaProvider) EditComment(ctx context.Context, owner, reponame string, number int, commentID int64, body string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/comments/%d", owner, reponame, commentID)
+	return errors.Wrap(p.do(ctx, http.MethodPatch, path, map[string]string{"body": body}, nil), "updating PR comment")
+}
+
+func (p *GiteaProvider) baseURL() string {
+	return p.BaseURL
+}
+
+func (p *GiteaProvider) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return http.DefaultClient
+}
+
+func (p *GiteaProvider) do(ctx context.Context, method, path string, reqBody, respBody any) error {
+	var r io.Reader
+	if reqBody != nil {
+		b, err := json.Marshal(reqBody)
+		if err != nil {
+			return errors.Wrap(err, "marshaling request body")
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL()+"/api/v1"+path, r)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+	req.Header.Set("Authorization", "token "+p.Token)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Gitea API error: %s: %s", resp.Status, b)
+	}
+
+	if respBody != nil {
+		if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+			return errors.Wrap(err, "decoding response body")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package prcomment
+
+import (
+	"context"
+	"time"
+
+	"github.com/bobg/errors"
+	"github.com/google/go-github/v62/github"
+)
+
+// GitHubProvider implements Provider on top of the GitHub REST API.
+type GitHubProvider struct {
+	prs    prsIntf
+	issues issuesIntf
+
+	// Since, if non-zero, restricts ListComments to comments updated at or after this time,
+	// so that a lookup on a long-lived pull request doesn't have to page through old comments.
+	Since time.Time
+
+	// MaxRateLimitRetries bounds how many times ListComments retries after a rate-limit error
+	// before giving up. The zero value means defaultMaxRateLimitRetries.
+	MaxRateLimitRetries int
+}
+
+// NewGitHubProvider creates a Provider backed by client.
+func NewGitHubProvider(client *github.Client) *GitHubProvider {
+	return &GitHubProvider{prs: client.PullRequests, issues: client.Issues}
+}
+
+const defaultMaxRateLimitRetries = 5
+
+type prsIntf interface {
+	Get(ctx context.Context, owner, reponame string, number int) (*github.PullRequest, *github.Response, error)
+}
+
+type issuesIntf interface {
+	CreateComment(ctx context.Context, owner, reponame string, num int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	EditComment(ctx context.Context, owner, reponame string, commentID int64, newComment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	ListComments(ctx context.Context, owner, reponame string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+}
+
+func (p *GitHubProvider) GetPR(ctx context.Context, owner, reponame string, number int) (*PullRequest, error) {
+	pr, _, err := p.prs.Get(ctx, owner, reponame, number)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting pull request")
+	}
+	return &PullRequest{
+		Number:  pr.GetNumber(),
+		Title:   pr.GetTitle(),
+		Body:    pr.GetBody(),
+		State:   pr.GetState(),
+		User:    pr.GetUser().GetLogin(),
+		HeadRef: pr.GetHead().GetRef(),
+		HeadSHA: pr.GetHead().GetSHA(),
+		BaseRef: pr.GetBase().GetRef(),
+		BaseSHA: pr.GetBase().GetSHA(),
+	}, nil
+}
+
+func (p *GitHubProvider) ListComments(ctx context.Context, owner, reponame string, number int) ([]*Comment, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if !p.Since.IsZero() {
+		opts.Since = &p.Since
+	}
+
+	var out []*Comment
+	for {
+		comments, resp, err := p.listCommentsPage(ctx, owner, reponame, number, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing PR comments")
+		}
+		for _, c := range comments {
+			out = append(out, &Comment{ID: c.GetID(), Body: c.GetBody(), User: c.GetUser().GetLogin()})
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// listCommentsPage fetches a single page of comments,
+// retrying with a bounded backoff if GitHub reports a primary or secondary rate limit.
+func (p *GitHubProvider) listCommentsPage(ctx context.Context, owner, reponame string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	maxRetries := p.MaxRateLimitRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRateLimitRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		comments, resp, err := p.issues.ListComments(ctx, owner, reponame, number, opts)
+		if err == nil {
+			return comments, resp, nil
+		}
+
+		wait, isRateLimit := rateLimitWait(err)
+		if !isRateLimit || attempt >= maxRetries {
+			return nil, nil, err
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// rateLimitWait reports how long to wait before retrying a request that failed with err,
+// if err is a GitHub primary or secondary rate-limit error.
+func rateLimitWait(err error) (time.Duration, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return time.Until(rateErr.Rate.Reset.Time), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return time.Minute, true
+	}
+
+	return 0, false
+}
+
+func (p *GitHubProvider) CreateComment(ctx context.Context, owner, reponame string, number int, body string) (*Comment, error) {
+	c, _, err := p.issues.CreateComment(ctx, owner, reponame, number, &github.IssueComment{Body: &body})
+	if err != nil {
+		return nil, errors.Wrap(err, "adding PR comment")
+	}
+	return &Comment{ID: c.GetID(), Body: c.GetBody(), User: c.GetUser().GetLogin()}, nil
+}
+
+func (p *GitHubProvider) EditComment(ctx context.Context, owner, reponame string, number int, commentID int64, body string) error {
+	_, _, err := p.issues.EditComment(ctx, owner, reponame, commentID, &github.IssueComment{Body: &body})
+	return errors.Wrap(err, "updating PR comment")
+}
@@ -0,0 +1,129 @@
+package prcomment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	t.Run("rate limit error", func(t *testing.T) {
+		reset := time.Now().Add(time.Minute)
+		err := &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}}
+		wait, ok := rateLimitWait(err)
+		if !ok {
+			t.Fatal("expected ok=true for a RateLimitError")
+		}
+		if wait <= 0 || wait > time.Minute {
+			t.Errorf("wait = %v, want something close to but not over a minute", wait)
+		}
+	})
+
+	t.Run("abuse rate limit error with RetryAfter", func(t *testing.T) {
+		retryAfter := 30 * time.Second
+		err := &github.AbuseRateLimitError{RetryAfter: &retryAfter}
+		wait, ok := rateLimitWait(err)
+		if !ok {
+			t.Fatal("expected ok=true for an AbuseRateLimitError")
+		}
+		if wait != retryAfter {
+			t.Errorf("wait = %v, want %v", wait, retryAfter)
+		}
+	})
+
+	t.Run("abuse rate limit error without RetryAfter", func(t *testing.T) {
+		err := &github.AbuseRateLimitError{}
+		wait, ok := rateLimitWait(err)
+		if !ok {
+			t.Fatal("expected ok=true for an AbuseRateLimitError")
+		}
+		if wait != time.Minute {
+			t.Errorf("wait = %v, want %v", wait, time.Minute)
+		}
+	})
+
+	t.Run("non-rate-limit error", func(t *testing.T) {
+		if _, ok := rateLimitWait(context.Canceled); ok {
+			t.Error("expected ok=false for an unrelated error")
+		}
+	})
+}
+
+// fakeIssues implements issuesIntf, returning errs[0], errs[1], ... on successive calls
+// to ListComments (repeating the last one once exhausted) and counting how many were made.
+type fakeIssues struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeIssues) CreateComment(ctx context.Context, owner, reponame string, num int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeIssues) EditComment(ctx context.Context, owner, reponame string, commentID int64, newComment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeIssues) ListComments(ctx context.Context, owner, reponame string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	i := f.calls
+	if i >= len(f.errs) {
+		i = len(f.errs) - 1
+	}
+	f.calls++
+	err := f.errs[i]
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*github.IssueComment{{ID: github.Int64(1)}}, &github.Response{}, nil
+}
+
+func TestListCommentsPageRetriesThenSucceeds(t *testing.T) {
+	pastReset := time.Now().Add(-time.Second) // already elapsed: wait is <= 0, so no real sleep
+	rateErr := &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: pastReset}}}
+
+	issues := &fakeIssues{errs: []error{rateErr, nil}}
+	p := &GitHubProvider{issues: issues}
+
+	comments, resp, err := p.listCommentsPage(context.Background(), "owner", "repo", 1, &github.IssueListCommentsOptions{})
+	if err != nil {
+		t.Fatalf("listCommentsPage: %v", err)
+	}
+	if resp == nil || len(comments) != 1 {
+		t.Fatalf("unexpected result: comments=%v resp=%v", comments, resp)
+	}
+	if issues.calls != 2 {
+		t.Errorf("calls = %d, want 2 (one rate-limited, one success)", issues.calls)
+	}
+}
+
+func TestListCommentsPageGivesUpAfterMaxRetries(t *testing.T) {
+	retryAfter := time.Millisecond
+	abuseErr := &github.AbuseRateLimitError{RetryAfter: &retryAfter}
+
+	// MaxRateLimitRetries's zero value means "use the default", so use 1 to bound retries tightly.
+	issues := &fakeIssues{errs: []error{abuseErr, abuseErr}}
+	p := &GitHubProvider{issues: issues, MaxRateLimitRetries: 1}
+
+	_, _, err := p.listCommentsPage(context.Background(), "owner", "repo", 1, &github.IssueListCommentsOptions{})
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if issues.calls != 2 {
+		t.Errorf("calls = %d, want 2 (one retry, then give up with MaxRateLimitRetries=1)", issues.calls)
+	}
+}
+
+func TestListCommentsPagePassesThroughNonRateLimitError(t *testing.T) {
+	issues := &fakeIssues{errs: []error{context.Canceled}}
+	p := &GitHubProvider{issues: issues}
+
+	_, _, err := p.listCommentsPage(context.Background(), "owner", "repo", 1, &github.IssueListCommentsOptions{})
+	if err != context.Canceled {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+	if issues.calls != 1 {
+		t.Errorf("calls = %d, want 1 (non-rate-limit errors are not retried)", issues.calls)
+	}
+}
@@ -0,0 +1,186 @@
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bobg/errors"
+)
+
+// GitLabProvider implements Provider on top of the GitLab REST API,
+// posting and updating merge-request notes.
+//
+// https://docs.gitlab.com/ee/api/notes.html#merge-requests
+type GitLabProvider struct {
+	// BaseURL is the root of the GitLab REST API, e.g. "https://gitlab.example.com/api/v4".
+	// It defaults to "https://gitlab.com/api/v4" if empty.
+	BaseURL string
+
+	// Token is a personal, project, or group access token, sent as a PRIVATE-TOKEN header.
+	Token string
+
+	// Client is the http.Client used to make requests. It defaults to http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewGitLabProvider creates a Provider backed by the GitLab instance at baseURL
+// (e.g. "https://gitlab.example.com/api/v4"), authenticating with token.
+func NewGitLabProvider(baseURL, token string) *GitLabProvider {
+	return &GitLabProvider{BaseURL: baseURL, Token: token}
+}
+
+type gitlabMergeRequest struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	Author       gitlabUser `json:"author"`
+	SourceBranch string     `json:"source_branch"`
+	SHA          string     `json:"sha"`
+	TargetBranch string     `json:"target_branch"`
+	DiffRefs     struct {
+		BaseSHA string `json:"base_sha"`
+	} `json:"diff_refs"`
+}
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabNote struct {
+	ID     int64      `json:"id"`
+	Body   string     `json:"body"`
+	Author gitlabUser `json:"author"`
+}
+
+func (p *GitLabProvider) GetPR(ctx context.Context, owner, reponame string, number int) (*PullRequest, error) {
+	var mr gitlabMergeRequest
+	if _, err := p.do(ctx, http.MethodGet, p.mrPath(owner, reponame, number), nil, &mr); err != nil {
+		return nil, errors.Wrap(err, "getting merge request")
+	}
+	return &PullRequest{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		Body:    mr.Description,
+		State:   mr.State,
+		User:    mr.Author.Username,
+		HeadRef: mr.SourceBranch,
+		HeadSHA: mr.SHA,
+		BaseRef: mr.TargetBranch,
+		BaseSHA: mr.DiffRefs.BaseSHA,
+	}, nil
+}
+
+func (p *GitLabProvider) ListComments(ctx context.Context, owner, reponame string, number int) ([]*Comment, error) {
+	var out []*Comment
+	path := p.mrPath(owner, reponame, number) + "/notes?per_page=100"
+	for path != "" {
+		var notes []gitlabNote
+		next, err := p.do(ctx, http.MethodGet, path, nil, &notes)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing merge request notes")
+		}
+		for _, n := range notes {
+			out = append(out, &Comment{ID: n.ID, Body: n.Body, User: n.Author.Username})
+		}
+		path = next
+	}
+	return out, nil
+}
+
+func (p *GitLabProvider) CreateComment(ctx context.Context, owner, reponame string, number int, body string) (*Comment, error) {
+	var note gitlabNote
+	if _, err := p.do(ctx, http.MethodPost, p.mrPath(owner, reponame, number)+"/notes", map[string]string{"body": body}, &note); err != nil {
+		return nil, errors.Wrap(err, "adding merge request note")
+	}
+	return &Comment{ID: note.ID, Body: note.Body, User: note.Author.Username}, nil
+}
+
+func (p *GitLabProvider) EditComment(ctx context.Context, owner, reponame string, number int, commentID int64, body string) error {
+	path := fmt.Sprintf("%s/notes/%d", p.mrPath(owner, reponame, number), commentID)
+	_, err := p.do(ctx, http.MethodPut, path, map[string]string{"body": body}, nil)
+	return errors.Wrap(err, "updating merge request note")
+}
+
+func (p *GitLabProvider) mrPath(owner, reponame string, number int) string {
+	project := url.PathEscape(owner + "/" + reponame)
+	return fmt.Sprintf("/projects/%s/merge_requests/%d", project, number)
+}
+
+func (p *GitLabProvider) baseURL() string {
+	if p.BaseURL != "" {
+		return p.BaseURL
+	}
+	return "https://gitlab.com/api/v4"
+}
+
+func (p *GitLabProvider) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return http.DefaultClient
+}
+
+// do issues one request against the GitLab API and returns the path of the next page, if any,
+// per GitLab's Link-header pagination (https://docs.gitlab.com/ee/api/rest/#pagination).
+func (p *GitLabProvider) do(ctx context.Context, method, path string, reqBody, respBody any) (next string, err error) {
+	var r io.Reader
+	if reqBody != nil {
+		b, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", errors.Wrap(err, "marshaling request body")
+		}
+		r = bytes.NewReader(b)
+	}
+
+	url := path
+	if !strings.HasPrefix(path, "http") {
+		url = p.baseURL() + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return "", errors.Wrap(err, "building request")
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.Token)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitLab API error: %s: %s", resp.Status, b)
+	}
+
+	if respBody != nil {
+		if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+			return "", errors.Wrap(err, "decoding response body")
+		}
+	}
+
+	return gitlabNextPage(resp.Header.Get("Link")), nil
+}
+
+var gitlabNextLinkPat = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// gitlabNextPage extracts the rel="next" target from an RFC 8288 Link header, or "" if there isn't one.
+func gitlabNextPage(link string) string {
+	m := gitlabNextLinkPat.FindStringSubmatch(link)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
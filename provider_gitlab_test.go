@@ -0,0 +1,35 @@
+package prcomment
+
+import "testing"
+
+func TestGitlabNextPage(t *testing.T) {
+	cases := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "next and last",
+			link: `<https://gitlab.example.com/api/v4/projects/1/merge_requests/2/notes?page=2>; rel="next", ` +
+				`<https://gitlab.example.com/api/v4/projects/1/merge_requests/2/notes?page=5>; rel="last"`,
+			want: "https://gitlab.example.com/api/v4/projects/1/merge_requests/2/notes?page=2",
+		},
+		{
+			name: "no next on last page",
+			link: `<https://gitlab.example.com/api/v4/projects/1/merge_requests/2/notes?page=1>; rel="first"`,
+			want: "",
+		},
+		{
+			name: "empty header",
+			link: "",
+			want: "",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := gitlabNextPage(c.link); got != c.want {
+				t.Errorf("gitlabNextPage(%q) = %q, want %q", c.link, got, c.want)
+			}
+		})
+	}
+}
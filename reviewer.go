@@ -0,0 +1,237 @@
+package prcomment
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bobg/errors"
+	"github.com/google/go-github/v62/github"
+)
+
+// InlineComment is a single file/line comment to attach to a pull-request review.
+//
+// Line is the line number (not diff position) in the file as it appears on Side.
+// For a multi-line comment, StartLine and StartSide describe where the comment begins;
+// StartSide defaults to Side when left empty.
+type InlineComment struct {
+	Path      string
+	Side      string // "LEFT" or "RIGHT"
+	Line      int
+	StartLine int
+	StartSide string
+	Body      string
+}
+
+// InvalidLineAction tells a Reviewer what to do with an InlineComment
+// whose file/line does not appear in the pull request's diff,
+// which GitHub would otherwise reject with an error.
+type InvalidLineAction int
+
+const (
+	// AppendToBody appends the text of an out-of-diff InlineComment to the review body. It is the default.
+	AppendToBody InvalidLineAction = iota
+
+	// DropInvalidLines silently discards out-of-diff InlineComments.
+	DropInvalidLines
+)
+
+// Reviewer is an object whose method AddOrUpdate posts a pull-request review,
+// with optional inline file/line comments, or replaces an existing one.
+//
+// Unlike a Commenter, which adds a single issue-style comment to a pull request,
+// a Reviewer posts all of its inline comments in a single review,
+// which is how GitHub expects lint- or CI-style feedback to be delivered without spamming the PR.
+type Reviewer struct {
+	// IsReview, if non-nil, is a function that returns true if a given review is the one to replace.
+	// When it finds a match, AddOrUpdate dismisses the old review before posting the new one
+	// (GitHub has no API for updating a review's comments in place).
+	IsReview func(*github.PullRequestReview) bool
+
+	// OnInvalidLine controls what happens to inline comments that don't land on a line
+	// present in the pull request's diff. The zero value is AppendToBody.
+	OnInvalidLine InvalidLineAction
+
+	review func(context.Context, *github.PullRequest) (body string, comments []InlineComment, event string, err error)
+	prs    reviewsIntf
+}
+
+type reviewsIntf interface {
+	Get(ctx context.Context, owner, reponame string, number int) (*github.PullRequest, *github.Response, error)
+	ListFiles(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
+	ListReviews(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	CreateReview(ctx context.Context, owner, reponame string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	DismissReview(ctx context.Context, owner, reponame string, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error)
+}
+
+// NewReviewer creates a new Reviewer object.
+// The review function is called to produce the review body, inline comments,
+// and review event (one of "COMMENT", "APPROVE", "REQUEST_CHANGES", or "PENDING") for a given pull request.
+func NewReviewer(client *github.Client, review func(context.Context, *github.PullRequest) (string, []InlineComment, string, error)) *Reviewer {
+	return &Reviewer{
+		review: review,
+		prs:    client.PullRequests,
+	}
+}
+
+// AddOrUpdate posts a new pull-request review, replacing any existing review matched by IsReview.
+func (r Reviewer) AddOrUpdate(ctx context.Context, owner, reponame string, prnum int) error {
+	pr, _, err := r.prs.Get(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	body, comments, event, err := r.review(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "getting review body")
+	}
+
+	files, err := r.listFiles(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "listing PR files")
+	}
+	valid := validDiffLines(files)
+
+	var draftComments []*github.DraftReviewComment
+	for _, c := range comments {
+		if !valid[diffLineKey{path: c.Path, side: c.Side, line: c.Line}] {
+			if r.OnInvalidLine == DropInvalidLines {
+				continue
+			}
+			body += fmt.Sprintf("\n\n**%s, line %d:**\n\n%s", c.Path, c.Line, c.Body)
+			continue
+		}
+		draftComments = append(draftComments, c.draftReviewComment())
+	}
+
+	if r.IsReview != nil {
+		if err := r.dismissExisting(ctx, owner, reponame, prnum, body); err != nil {
+			return err
+		}
+	}
+
+	reviewReq := &github.PullRequestReviewRequest{
+		CommitID: pr.GetHead().SHA,
+		Body:     &body,
+		Comments: draftComments,
+	}
+	// GitHub has no "PENDING" review-event enum value: a pending review is created
+	// by omitting Event entirely, not by sending the literal string.
+	if event != "PENDING" {
+		reviewReq.Event = &event
+	}
+	_, _, err = r.prs.CreateReview(ctx, owner, reponame, prnum, reviewReq)
+	return errors.Wrap(err, "adding PR review")
+}
+
+func (r Reviewer) dismissExisting(ctx context.Context, owner, reponame string, prnum int, reason string) error {
+	reviews, err := r.listReviews(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "listing PR reviews")
+	}
+	for _, review := range reviews {
+		if !r.IsReview(review) {
+			continue
+		}
+		_, _, err := r.prs.DismissReview(ctx, owner, reponame, prnum, review.GetID(), &github.PullRequestReviewDismissalRequest{
+			Message: github.String(reason),
+		})
+		return errors.Wrap(err, "dismissing existing PR review")
+	}
+	return nil
+}
+
+func (r Reviewer) listFiles(ctx context.Context, owner, reponame string, prnum int) ([]*github.CommitFile, error) {
+	var out []*github.CommitFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := r.prs.ListFiles(ctx, owner, reponame, prnum, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, files...)
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (r Reviewer) listReviews(ctx context.Context, owner, reponame string, prnum int) ([]*github.PullRequestReview, error) {
+	var out []*github.PullRequestReview
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := r.prs.ListReviews(ctx, owner, reponame, prnum, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, reviews...)
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c InlineComment) draftReviewComment() *github.DraftReviewComment {
+	d := &github.DraftReviewComment{
+		Path: github.String(c.Path),
+		Body: github.String(c.Body),
+		Side: github.String(c.Side),
+		Line: github.Int(c.Line),
+	}
+	if c.StartLine != 0 {
+		startSide := c.StartSide
+		if startSide == "" {
+			startSide = c.Side
+		}
+		d.StartLine = github.Int(c.StartLine)
+		d.StartSide = github.String(startSide)
+	}
+	return d
+}
+
+type diffLineKey struct {
+	path, side string
+	line       int
+}
+
+// validDiffLines returns the set of path/side/line triples that appear in the unified diffs
+// of the given files, i.e. the lines GitHub will accept an inline review comment on.
+func validDiffLines(files []*github.CommitFile) map[diffLineKey]bool {
+	valid := make(map[diffLineKey]bool)
+	for _, f := range files {
+		oldLine, newLine := 0, 0
+		for _, line := range strings.Split(f.GetPatch(), "\n") {
+			if m := hunkHeaderPat.FindStringSubmatch(line); m != nil {
+				oldLine, _ = strconv.Atoi(m[1])
+				newLine, _ = strconv.Atoi(m[2])
+				continue
+			}
+			if oldLine == 0 && newLine == 0 {
+				continue // not yet inside a hunk
+			}
+			if strings.HasPrefix(line, "\\") {
+				continue // e.g. "\ No newline at end of file"; not a diff line itself
+			}
+			switch {
+			case strings.HasPrefix(line, "-"):
+				valid[diffLineKey{f.GetFilename(), "LEFT", oldLine}] = true
+				oldLine++
+			case strings.HasPrefix(line, "+"):
+				valid[diffLineKey{f.GetFilename(), "RIGHT", newLine}] = true
+				newLine++
+			default:
+				valid[diffLineKey{f.GetFilename(), "LEFT", oldLine}] = true
+				valid[diffLineKey{f.GetFilename(), "RIGHT", newLine}] = true
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return valid
+}
+
+var hunkHeaderPat = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
@@ -0,0 +1,97 @@
+package prcomment
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestValidDiffLines(t *testing.T) {
+	patch := "@@ -1,3 +1,4 @@\n" +
+		" unchanged\n" +
+		"-removed\n" +
+		"+added one\n" +
+		"+added two\n" +
+		" trailing"
+
+	files := []*github.CommitFile{
+		{
+			Filename: github.String("foo.go"),
+			Patch:    github.String(patch),
+		},
+	}
+
+	got := validDiffLines(files)
+
+	cases := []struct {
+		key  diffLineKey
+		want bool
+	}{
+		{diffLineKey{"foo.go", "LEFT", 1}, true},   // unchanged context line
+		{diffLineKey{"foo.go", "RIGHT", 1}, true},  // same context line, right side
+		{diffLineKey{"foo.go", "LEFT", 2}, true},   // removed line
+		{diffLineKey{"foo.go", "RIGHT", 2}, true},  // first added line
+		{diffLineKey{"foo.go", "RIGHT", 3}, true},  // second added line
+		{diffLineKey{"foo.go", "LEFT", 3}, true},   // trailing context, left side
+		{diffLineKey{"foo.go", "RIGHT", 4}, true},  // trailing context, right side
+		{diffLineKey{"foo.go", "LEFT", 4}, false},  // past the end of the hunk
+		{diffLineKey{"bar.go", "RIGHT", 1}, false}, // different file entirely
+	}
+	for _, c := range cases {
+		if got[c.key] != c.want {
+			t.Errorf("validDiffLines[%+v] = %v, want %v", c.key, got[c.key], c.want)
+		}
+	}
+}
+
+func TestValidDiffLinesMultipleHunks(t *testing.T) {
+	patch := "@@ -1,2 +1,2 @@\n" +
+		"-old\n" +
+		"+new\n" +
+		"@@ -10,1 +10,1 @@\n" +
+		"-old2\n" +
+		"+new2\n"
+
+	files := []*github.CommitFile{
+		{Filename: github.String("baz.go"), Patch: github.String(patch)},
+	}
+
+	got := validDiffLines(files)
+
+	if !got[diffLineKey{"baz.go", "RIGHT", 1}] {
+		t.Error("expected line 1 (first hunk) to be valid")
+	}
+	if !got[diffLineKey{"baz.go", "RIGHT", 10}] {
+		t.Error("expected line 10 (second hunk) to be valid")
+	}
+	if got[diffLineKey{"baz.go", "RIGHT", 2}] {
+		t.Error("did not expect line 2, which falls between hunks, to be valid")
+	}
+}
+
+func TestValidDiffLinesNoNewlineMarker(t *testing.T) {
+	patch := "@@ -1,1 +1,1 @@\n" +
+		"-old\n" +
+		"\\ No newline at end of file\n" +
+		"+new\n" +
+		"\\ No newline at end of file"
+
+	files := []*github.CommitFile{
+		{Filename: github.String("qux.go"), Patch: github.String(patch)},
+	}
+
+	got := validDiffLines(files)
+
+	if !got[diffLineKey{"qux.go", "LEFT", 1}] {
+		t.Error("expected removed line 1 to be valid")
+	}
+	if !got[diffLineKey{"qux.go", "RIGHT", 1}] {
+		t.Error("expected added line 1 to be valid")
+	}
+	if got[diffLineKey{"qux.go", "LEFT", 2}] {
+		t.Error("\"\\ No newline\" marker must not be counted as an extra context line")
+	}
+	if got[diffLineKey{"qux.go", "RIGHT", 2}] {
+		t.Error("\"\\ No newline\" marker must not be counted as an extra context line")
+	}
+}
@@ -0,0 +1,169 @@
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/bobg/errors"
+	"github.com/google/go-github/v62/github"
+)
+
+// TemplateData is what a TemplateCommenter's template renders from.
+type TemplateData struct {
+	PR      *github.PullRequest
+	Files   []*github.CommitFile
+	Commits []*github.RepositoryCommit
+	Labels  []string
+	Diff    string
+	Data    any
+}
+
+// defaultMaxDiffBytes keeps a rendered Diff comfortably under GitHub's ~65536-byte comment body limit,
+// leaving room for the rest of the template.
+const defaultMaxDiffBytes = 60000
+
+// TemplateCommenter is a Commenter whose body is rendered from a text/template instead of
+// a hand-written body func, with template-context helpers for the PR/diff data bots most commonly need:
+// the changed-files list (with each file's additions/deletions), the commit list, labels,
+// and a size-limited unified-diff snippet. It only supports GitHub, since its helpers call GitHub-specific APIs.
+type TemplateCommenter struct {
+	Commenter
+
+	// MaxDiffBytes caps the size of the rendered Diff snippet, not counting its truncation marker.
+	// The zero value means defaultMaxDiffBytes.
+	MaxDiffBytes int
+
+	prs  prsFilesIntf
+	tmpl *template.Template
+	data func(context.Context, *github.PullRequest) (any, error)
+}
+
+type prsFilesIntf interface {
+	Get(ctx context.Context, owner, reponame string, number int) (*github.PullRequest, *github.Response, error)
+	ListFiles(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
+	ListCommits(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+}
+
+// NewTemplateCommenter creates a TemplateCommenter that renders tmplText (parsed as a text/template)
+// against a TemplateData built from the pull request and whatever data returns.
+// data may be nil if the template needs no caller-supplied data beyond TemplateData's other fields.
+func NewTemplateCommenter(client *github.Client, tmplText string, data func(context.Context, *github.PullRequest) (any, error)) (*TemplateCommenter, error) {
+	tmpl, err := template.New("prcomment").Parse(tmplText)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing template")
+	}
+	return &TemplateCommenter{
+		Commenter: Commenter{provider: NewGitHubProvider(client)},
+		prs:       client.PullRequests,
+		tmpl:      tmpl,
+		data:      data,
+	}, nil
+}
+
+// AddOrUpdate renders tc's template against the pull request and adds it as a new PR comment,
+// or edits an existing one found via IsComment or WithMarker.
+func (tc *TemplateCommenter) AddOrUpdate(ctx context.Context, owner, reponame string, prnum int) error {
+	pr, _, err := tc.prs.Get(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	files, err := tc.listFiles(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "listing PR files")
+	}
+
+	commits, err := tc.listCommits(ctx, owner, reponame, prnum)
+	if err != nil {
+		return errors.Wrap(err, "listing PR commits")
+	}
+
+	var data any
+	if tc.data != nil {
+		if data, err = tc.data(ctx, pr); err != nil {
+			return errors.Wrap(err, "fetching template data")
+		}
+	}
+
+	labels := make([]string, len(pr.Labels))
+	for i, l := range pr.Labels {
+		labels[i] = l.GetName()
+	}
+
+	td := TemplateData{
+		PR:      pr,
+		Files:   files,
+		Commits: commits,
+		Labels:  labels,
+		Diff:    diffSnippet(files, tc.maxDiffBytes()),
+		Data:    data,
+	}
+
+	var buf bytes.Buffer
+	if err := tc.tmpl.Execute(&buf, td); err != nil {
+		return errors.Wrap(err, "executing template")
+	}
+
+	return tc.Commenter.postOrUpdate(ctx, owner, reponame, prnum, buf.String())
+}
+
+func (tc *TemplateCommenter) maxDiffBytes() int {
+	if tc.MaxDiffBytes > 0 {
+		return tc.MaxDiffBytes
+	}
+	return defaultMaxDiffBytes
+}
+
+func (tc *TemplateCommenter) listFiles(ctx context.Context, owner, reponame string, prnum int) ([]*github.CommitFile, error) {
+	var out []*github.CommitFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := tc.prs.ListFiles(ctx, owner, reponame, prnum, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, files...)
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (tc *TemplateCommenter) listCommits(ctx context.Context, owner, reponame string, prnum int) ([]*github.RepositoryCommit, error) {
+	var out []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, resp, err := tc.prs.ListCommits(ctx, owner, reponame, prnum, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, commits...)
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// diffSnippet renders a unified-diff-style snippet from files' patches, including whole files only while they fit in maxBytes.
+// When the next file would not fit, it stops and appends a short truncation marker, which is not counted against maxBytes.
+func diffSnippet(files []*github.CommitFile, maxBytes int) string {
+	var buf bytes.Buffer
+	for _, f := range files {
+		if f.GetPatch() == "" {
+			continue
+		}
+		header := fmt.Sprintf("--- a/%s\n+++ b/%s\n", f.GetFilename(), f.GetFilename())
+		if buf.Len()+len(header)+len(f.GetPatch())+1 > maxBytes { // +1 for the newline after the patch
+			buf.WriteString("... (diff truncated)\n")
+			break
+		}
+		buf.WriteString(header)
+		buf.WriteString(f.GetPatch())
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
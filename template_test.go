@@ -0,0 +1,102 @@
+package prcomment
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// fakePRs implements prsFilesIntf with canned, single-page responses.
+type fakePRs struct {
+	pr      *github.PullRequest
+	files   []*github.CommitFile
+	commits []*github.RepositoryCommit
+}
+
+func (f *fakePRs) Get(ctx context.Context, owner, reponame string, number int) (*github.PullRequest, *github.Response, error) {
+	return f.pr, &github.Response{}, nil
+}
+
+func (f *fakePRs) ListFiles(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	return f.files, &github.Response{}, nil
+}
+
+func (f *fakePRs) ListCommits(ctx context.Context, owner, reponame string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	return f.commits, &github.Response{}, nil
+}
+
+func TestTemplateCommenterAddOrUpdate(t *testing.T) {
+	tmpl, err := template.New("prcomment").Parse(
+		"PR #{{.PR.GetNumber}}: {{.PR.GetTitle}}\n" +
+			"Files: {{range .Files}}{{.GetFilename}} {{end}}\n" +
+			"Commits: {{range .Commits}}{{.GetSHA}} {{end}}\n" +
+			"Labels: {{range .Labels}}{{.}} {{end}}",
+	)
+	if err != nil {
+		t.Fatalf("parsing template: %v", err)
+	}
+
+	prs := &fakePRs{
+		pr: &github.PullRequest{
+			Number: github.Int(6),
+			Title:  github.String("Add templated comments"),
+			Labels: []*github.Label{{Name: github.String("enhancement")}},
+		},
+		files:   []*github.CommitFile{{Filename: github.String("template.go")}},
+		commits: []*github.RepositoryCommit{{SHA: github.String("abc123")}},
+	}
+	provider := &fakeProvider{}
+
+	tc := &TemplateCommenter{
+		Commenter: Commenter{provider: provider},
+		prs:       prs,
+		tmpl:      tmpl,
+	}
+
+	if err := tc.AddOrUpdate(context.Background(), "bobg", "prcomment", 6); err != nil {
+		t.Fatalf("AddOrUpdate: %v", err)
+	}
+	if !provider.created {
+		t.Fatal("expected a new comment to be created")
+	}
+
+	body := provider.lastBody
+	for _, want := range []string{
+		"PR #6: Add templated comments",
+		"Files: template.go",
+		"Commits: abc123",
+		"Labels: enhancement",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("rendered body %q does not contain %q", body, want)
+		}
+	}
+}
+
+func TestDiffSnippetTruncatesAtMaxBytes(t *testing.T) {
+	files := []*github.CommitFile{
+		{Filename: github.String("a.go"), Patch: github.String("@@ -1 +1 @@\n-a\n+A")},
+		{Filename: github.String("b.go"), Patch: github.String("@@ -1 +1 @@\n-b\n+B")},
+	}
+
+	full := diffSnippet(files, 1<<20)
+	if !strings.Contains(full, "a.go") || !strings.Contains(full, "b.go") {
+		t.Fatalf("expected both files in an untruncated snippet, got %q", full)
+	}
+
+	// A budget that fits the first file's header+patch but not the second's forces truncation.
+	budget := len("--- a/a.go\n+++ b/a.go\n") + len(files[0].GetPatch()) + 1
+	truncated := diffSnippet(files, budget)
+	if !strings.Contains(truncated, "a.go") {
+		t.Errorf("expected the first file to still appear, got %q", truncated)
+	}
+	if strings.Contains(truncated, "b.go") {
+		t.Errorf("expected the second file to be dropped once the budget is exceeded, got %q", truncated)
+	}
+	if !strings.Contains(truncated, "(diff truncated)") {
+		t.Errorf("expected a truncation marker, got %q", truncated)
+	}
+}